@@ -0,0 +1,86 @@
+package bookstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned by request/form calls whose response status isn't
+// accepted as success, so callers can do errors.As(err, &apiErr) and branch
+// on e.g. apiErr.StatusCode == http.StatusNotFound instead of matching on
+// an error string.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    map[string]any
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("bookstack: %s (status %d): %s", e.Code, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("bookstack: status %d: %s", e.StatusCode, e.Message)
+}
+
+// apiErrorCode accepts either a numeric code (Bookstack mirrors the HTTP
+// status, e.g. 404) or a string code, without failing to unmarshal either
+// way.
+type apiErrorCode string
+
+func (c *apiErrorCode) UnmarshalJSON(data []byte) error {
+
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*c = apiErrorCode(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	*c = apiErrorCode(n.String())
+
+	return nil
+}
+
+type apiErrorBody struct {
+	Error struct {
+		Code    apiErrorCode   `json:"code"`
+		Message string         `json:"message"`
+		Details map[string]any `json:"details"`
+	} `json:"error"`
+}
+
+// parseAPIError builds an *APIError out of a non-2xx response. It first
+// tries Bookstack's documented {"error": {"code", "message", "details"}}
+// shape, falling back to Response's own parsing for the message text.
+func parseAPIError(resp *http.Response, raw []byte) error {
+
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+
+	var body apiErrorBody
+	if err := json.Unmarshal(raw, &body); err == nil {
+		apiErr.Code = string(body.Error.Code)
+		apiErr.Message = body.Error.Message
+		apiErr.Details = body.Error.Details
+	}
+
+	if apiErr.Message == "" {
+		msg := Response{}
+		if err := json.Unmarshal(raw, &msg); err == nil {
+			if msgErr := msg.Error(); msgErr != nil {
+				apiErr.Message = msgErr.Error()
+			}
+		}
+	}
+
+	return apiErr
+}
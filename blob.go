@@ -0,0 +1,64 @@
+package bookstack
+
+import (
+	"io"
+	"os"
+)
+
+// Blob is a seekable, sized source for an attachment upload. It mirrors
+// io.ReaderAt so large files never have to be buffered into memory to be
+// chunked, hashed, or retried.
+type Blob interface {
+	ReadAt(p []byte, off int64) (int, error)
+	Size() int64
+	Close() error
+}
+
+type bytesBlob struct {
+	data []byte
+}
+
+// NewBytesBlob wraps an in-memory payload as a Blob.
+func NewBytesBlob(data []byte) Blob {
+	return &bytesBlob{data: data}
+}
+
+func (b *bytesBlob) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(b.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, b.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+func (b *bytesBlob) Size() int64 { return int64(len(b.data)) }
+
+func (b *bytesBlob) Close() error { return nil }
+
+type fileBlob struct {
+	f    *os.File
+	size int64
+}
+
+// NewFileBlob wraps an *os.File as a Blob without reading it into memory.
+func NewFileBlob(f *os.File) (Blob, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileBlob{f: f, size: info.Size()}, nil
+}
+
+func (b *fileBlob) ReadAt(p []byte, off int64) (int, error) {
+	return b.f.ReadAt(p, off)
+}
+
+func (b *fileBlob) Size() int64 { return b.size }
+
+func (b *fileBlob) Close() error { return b.f.Close() }
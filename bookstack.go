@@ -22,6 +22,15 @@ type Bookstack struct {
 	limit       ratelimit.Limiter
 	log         *log.Logger
 	insecure    bool
+	transport   http.RoundTripper
+	client      *http.Client
+	retry       RetryPolicy
+	middleware  []Middleware
+	metrics     MetricsHook
+
+	uploadConcurrency int
+	uploadChunkSize   int
+	uploadProgress    UploadProgress
 }
 
 type Option func(*Bookstack)
@@ -52,113 +61,125 @@ func SetRateLimit(limit int) Option {
 	}
 }
 
-func New(opts ...Option) *Bookstack {
-
-	b := &Bookstack{
-		limit: ratelimit.New(180),
-		log:   log.New(ioutil.Discard, "", 0),
+// SetHTTPClient lets the caller inject a fully configured *http.Client
+// (custom transport, tracing, service-mesh client certs, an in-memory
+// test double, ...) instead of relying on http.DefaultClient.
+func SetHTTPClient(client *http.Client) Option {
+	return func(b *Bookstack) {
+		b.client = client
 	}
+}
 
-	for _, opt := range opts {
-		opt(b)
+// SetTransport sets the http.RoundTripper used by the Bookstack's
+// *http.Client, whether that client is the default one or one supplied via
+// SetHTTPClient, and regardless of the order the two options are passed to
+// New in.
+func SetTransport(transport http.RoundTripper) Option {
+	return func(b *Bookstack) {
+		b.transport = transport
 	}
-
-	return b
 }
 
-func (b *Bookstack) authorization() string {
-	return fmt.Sprintf("Token %s:%s", b.tokenID, b.tokenSecret)
+// SetInsecure skips TLS certificate verification on the Bookstack's private
+// *http.Client. It has no effect if SetTransport is also used, which always
+// takes precedence.
+func SetInsecure(insecure bool) Option {
+	return func(b *Bookstack) {
+		b.insecure = insecure
+	}
 }
 
-func (b *Bookstack) request(ctx context.Context, method, query string, data []byte) ([]byte, error) {
-
-	b.limit.Take()
-
-	url := fmt.Sprintf("%s/api/%s", strings.TrimRight(b.url, "/"), strings.TrimLeft(query, "/"))
+func New(opts ...Option) *Bookstack {
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
-	if err != nil {
-		return nil, err
+	b := &Bookstack{
+		limit:             ratelimit.New(180),
+		log:               log.New(ioutil.Discard, "", 0),
+		client:            &http.Client{},
+		uploadConcurrency: defaultUploadConcurrency,
+		uploadChunkSize:   defaultUploadChunkSize,
 	}
 
-	if len(data) > 0 {
-		req.Header.Add("Content-Type", "application/json")
+	for _, opt := range opts {
+		opt(b)
 	}
 
-	req.Header.Add("Authorization", b.authorization())
-
-	client := http.DefaultClient
-
-	if b.insecure {
-		client.Transport = &http.Transport{
+	switch {
+	case b.transport != nil:
+		b.client.Transport = b.transport
+	case b.insecure:
+		b.client.Transport = &http.Transport{
 			TLSClientConfig: &tls.Config{
 				InsecureSkipVerify: true,
 			},
 		}
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
+	return b
+}
 
-	raw, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+func (b *Bookstack) authorization() string {
+	return fmt.Sprintf("Token %s:%s", b.tokenID, b.tokenSecret)
+}
 
-	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
+func (b *Bookstack) request(ctx context.Context, method, query string, data []byte) ([]byte, error) {
 
-		return raw, nil
+	url := fmt.Sprintf("%s/api/%s", strings.TrimRight(b.url, "/"), strings.TrimLeft(query, "/"))
 
-	}
+	contentType := ""
 
-	msg := Response{}
+	var body bodyFunc
 
-	if err := json.Unmarshal(raw, &msg); err != nil {
-		return nil, err
+	if len(data) > 0 {
+		contentType = "application/json"
+		body = func() (io.ReadCloser, int64, error) {
+			return ioutil.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+		}
 	}
 
-	return nil, msg.Error()
+	return b.do(ctx, method, url, contentType, body, func(status int) bool {
+		return status == http.StatusOK || status == http.StatusNoContent
+	})
 
 }
 
+// bodyFunc produces a fresh, replayable request body and its size so that
+// retried attempts don't reuse an already-drained io.Reader.
+type bodyFunc func() (io.ReadCloser, int64, error)
+
+// Form is implemented by multipart payloads passed to Bookstack.form. body
+// must be callable more than once, since a retried attempt needs a fresh
+// io.ReadCloser positioned at the start.
 type Form interface {
-	Form() (string, io.Reader, error)
+	Form() (contentType string, body bodyFunc, err error)
 }
 
 func (b *Bookstack) form(ctx context.Context, method, query string, data Form) ([]byte, error) {
 
-	b.limit.Take()
-
 	url := fmt.Sprintf("%s/api/%s", strings.TrimRight(b.url, "/"), strings.TrimLeft(query, "/"))
 
-	client := http.DefaultClient
-
-	if b.insecure {
-		client.Transport = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		}
-	}
-
-	contentType, reader, err := data.Form()
+	contentType, body, err := data.Form()
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, ioutil.NopCloser(reader))
+	return b.do(ctx, method, url, contentType, body, func(status int) bool {
+		return status >= http.StatusOK && status <= http.StatusIMUsed
+	})
+
+}
+
+// do builds a request for method/url with the optional body and runs it
+// through the middleware chain (rate limiting, retries, auth, logging,
+// metrics, then any user middleware from Use), accepting responses whose
+// status code satisfies accept.
+func (b *Bookstack) do(ctx context.Context, method, url, contentType string, body bodyFunc, accept func(status int) bool) ([]byte, error) {
+
+	req, err := b.newRequest(ctx, method, url, contentType, body)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Add("Authorization", b.authorization())
-	req.Header.Add("Content-Type", contentType)
-
-	resp, err := client.Do(req)
+	resp, err := b.chain()(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -170,27 +191,63 @@ func (b *Bookstack) form(ctx context.Context, method, query string, data Form) (
 		return nil, err
 	}
 
-	// if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
-	if resp.StatusCode >= http.StatusOK && resp.StatusCode <= http.StatusIMUsed {
+	if accept(resp.StatusCode) {
 		return raw, nil
 	}
 
-	msg := Response{}
+	return nil, parseAPIError(resp, raw)
+}
+
+// newRequest builds the *http.Request for do, wiring req.GetBody so the
+// retry middleware can replay body on a retried attempt.
+func (b *Bookstack) newRequest(ctx context.Context, method, url, contentType string, body bodyFunc) (*http.Request, error) {
+
+	var reader io.ReadCloser
+	var size int64
+
+	if body != nil {
+		r, n, err := body()
+		if err != nil {
+			return nil, err
+		}
+		reader = r
+		size = n
+	}
 
-	if err := json.Unmarshal(raw, &msg); err != nil {
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
 		return nil, err
 	}
 
-	return nil, msg.Error()
+	if body != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			r, _, err := body()
+			return r, err
+		}
+	}
+
+	if size > 0 {
+		req.ContentLength = size
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
 
+	return req, nil
 }
 
 type Single interface {
-	User | Book | BookDetailed
+	User | Book | BookDetailed |
+		Chapter | ChapterDetailed |
+		Page | PageDetailed |
+		Shelf | ShelfDetailed |
+		Attachment | ImageGallery | Role | RecycleBinItem
 }
 
 type Group interface {
-	[]User | []Book
+	[]User | []Book | []Chapter | []Page | []Shelf |
+		[]Attachment | []ImageGallery | []Role | []RecycleBinItem
 }
 
 func ParseSingle[s Single](data []byte) (s, error) {
@@ -0,0 +1,158 @@
+package bookstack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ListParams controls pagination, sorting, and filtering for Iterate and
+// ListAll, mapping onto Bookstack's count/offset/sort/filter query
+// parameters.
+type ListParams struct {
+	Count   int
+	Offset  int
+	Sort    string
+	Filters map[string]string
+}
+
+func (p ListParams) values() url.Values {
+
+	q := url.Values{}
+
+	if p.Count > 0 {
+		q.Set("count", strconv.Itoa(p.Count))
+	}
+
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+
+	if p.Sort != "" {
+		q.Set("sort", p.Sort)
+	}
+
+	for k, v := range p.Filters {
+		q.Set(fmt.Sprintf("filter[%s]", k), v)
+	}
+
+	return q
+}
+
+// Result carries one page of items from Iterate, or the error that ended
+// the stream.
+type Result[g Group] struct {
+	Items g
+	Total int
+	Err   error
+}
+
+// Iterate walks every page of path (a Bookstack list endpoint such as
+// "books" or "pages"), honoring params' count/offset/sort/filter, and
+// streams each page as soon as it's fetched without buffering the whole
+// result set. The channel is closed once every page has been sent, the
+// context is canceled, or a page fails to fetch or parse - in which case
+// the last Result carries the error.
+func Iterate[g Group](ctx context.Context, b *Bookstack, path string, params ListParams) <-chan Result[g] {
+
+	out := make(chan Result[g])
+
+	go func() {
+		defer close(out)
+
+		offset := params.Offset
+
+		for {
+			page := params
+			page.Offset = offset
+
+			items, total, err := fetchPage[g](ctx, b, path, page)
+			if err != nil {
+				select {
+				case out <- Result[g]{Err: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			select {
+			case out <- Result[g]{Items: items, Total: total}:
+			case <-ctx.Done():
+				return
+			}
+
+			n := len(items)
+
+			if n == 0 || offset+n >= total {
+				return
+			}
+
+			offset += n
+		}
+	}()
+
+	return out
+}
+
+func fetchPage[g Group](ctx context.Context, b *Bookstack, path string, page ListParams) (g, int, error) {
+
+	raw, err := b.request(ctx, http.MethodGet, withQuery(path, page.values()), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r := Response{}
+
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.Error(); err != nil {
+		return nil, 0, err
+	}
+
+	var items g
+
+	if err := json.Unmarshal(r.Data, &items); err != nil {
+		return nil, 0, err
+	}
+
+	return items, r.Total, nil
+}
+
+func withQuery(path string, q url.Values) string {
+
+	query := q.Encode()
+	if query == "" {
+		return path
+	}
+
+	if strings.Contains(path, "?") {
+		return path + "&" + query
+	}
+
+	return path + "?" + query
+}
+
+// ListAll collects every page of path into a single slice, for callers
+// that don't need streaming.
+func ListAll[g Group](ctx context.Context, b *Bookstack, path string, params ListParams) (g, error) {
+
+	var all g
+	v := reflect.ValueOf(&all).Elem()
+
+	for res := range Iterate[g](ctx, b, path, params) {
+		if res.Err != nil {
+			return nil, res.Err
+		}
+
+		v.Set(reflect.AppendSlice(v, reflect.ValueOf(res.Items)))
+	}
+
+	return all, nil
+}
@@ -0,0 +1,65 @@
+package bookstack
+
+import "testing"
+
+func TestListParamsValues(t *testing.T) {
+	p := ListParams{
+		Count:  50,
+		Offset: 100,
+		Sort:   "-created_at",
+		Filters: map[string]string{
+			"name": "foo",
+		},
+	}
+
+	q := p.values()
+
+	cases := map[string]string{
+		"count":        "50",
+		"offset":       "100",
+		"sort":         "-created_at",
+		"filter[name]": "foo",
+	}
+
+	for key, want := range cases {
+		if got := q.Get(key); got != want {
+			t.Errorf("query[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestListParamsValuesOmitsZeroFields(t *testing.T) {
+	q := ListParams{}.values()
+
+	for _, key := range []string{"count", "offset", "sort"} {
+		if q.Has(key) {
+			t.Errorf("query unexpectedly has %q set to %q", key, q.Get(key))
+		}
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	cases := []struct {
+		name  string
+		path  string
+		query string
+		want  string
+	}{
+		{"no query", "books", "", "books"},
+		{"path without existing query", "books", "count=10", "books?count=10"},
+		{"path with existing query", "books?filter[tag]=x", "count=10", "books?filter[tag]=x&count=10"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			q := ListParams{}.values()
+			if c.query != "" {
+				q.Set("count", "10")
+			}
+
+			if got := withQuery(c.path, q); got != c.want {
+				t.Errorf("withQuery(%q, %q) = %q, want %q", c.path, c.query, got, c.want)
+			}
+		})
+	}
+}
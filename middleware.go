@@ -0,0 +1,180 @@
+package bookstack
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/ratelimit"
+)
+
+// RoundTrip performs a single HTTP round-trip within a middleware chain.
+type RoundTrip func(ctx context.Context, req *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTrip to add cross-cutting behaviour (rate
+// limiting, retries, auth, logging, metrics, ...) around the underlying
+// transport.
+type Middleware func(RoundTrip) RoundTrip
+
+// Use appends middlewares to run around every request/form call, outermost
+// first. They wrap the built-in rate-limiting, retry, auth, logging and
+// metrics middlewares, so they run once per logical call and do not see
+// the individual attempts a retry makes.
+func (b *Bookstack) Use(mw ...Middleware) {
+	b.middleware = append(b.middleware, mw...)
+}
+
+// chain assembles the full middleware stack around the http.Client call,
+// with the built-ins closest to the transport and user middlewares
+// outermost.
+func (b *Bookstack) chain() RoundTrip {
+
+	next := RoundTrip(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		return b.client.Do(req.WithContext(ctx))
+	})
+
+	// Applied innermost first: logging and rate-limiting run once per HTTP
+	// attempt (inside retry), while metrics wraps retry so it measures the
+	// whole logical call, not each individual attempt.
+	for _, mw := range []Middleware{
+		loggingMiddleware(b.log),
+		rateLimitMiddleware(b.limit),
+		retryMiddleware(b.retry),
+		metricsMiddleware(b.metrics),
+		authMiddleware(b.authorization),
+	} {
+		next = mw(next)
+	}
+
+	for i := len(b.middleware) - 1; i >= 0; i-- {
+		next = b.middleware[i](next)
+	}
+
+	return next
+}
+
+func authMiddleware(authorization func() string) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			req.Header.Set("Authorization", authorization())
+			return next(ctx, req)
+		}
+	}
+}
+
+func rateLimitMiddleware(limit ratelimit.Limiter) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			limit.Take()
+			return next(ctx, req)
+		}
+	}
+}
+
+// retryMiddleware retries requests whose response status is in
+// policy.RetryableStatusCodes, or that fail outright, using jittered
+// exponential backoff or a Retry-After header. It rebuilds the request
+// body from req.GetBody before each retry.
+func retryMiddleware(policy RetryPolicy) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+
+			var lastErr error
+			var delay time.Duration
+
+			for attempt := 0; attempt < policy.attempts(); attempt++ {
+
+				if attempt > 0 {
+					if err := sleep(ctx, delay); err != nil {
+						return nil, err
+					}
+
+					if req.GetBody != nil {
+						body, err := req.GetBody()
+						if err != nil {
+							return nil, err
+						}
+						req.Body = body
+					}
+				}
+
+				resp, err := next(ctx, req)
+				if err != nil {
+					lastErr = err
+					delay = policy.backoff(attempt)
+					continue
+				}
+
+				if attempt == policy.attempts()-1 || !policy.retryable(resp.StatusCode) {
+					return resp, nil
+				}
+
+				delay = policy.backoff(attempt)
+				if policy.RespectRetryAfter {
+					if d, ok := retryAfter(resp.Header); ok {
+						delay = d
+					}
+				}
+
+				resp.Body.Close()
+				lastErr = nil
+			}
+
+			return nil, lastErr
+		}
+	}
+}
+
+func loggingMiddleware(logger interface{ Printf(string, ...any) }) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+
+			start := time.Now()
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				logger.Printf("bookstack: %s %s failed after %s: %v", req.Method, req.URL, time.Since(start), err)
+				return nil, err
+			}
+
+			logger.Printf("bookstack: %s %s -> %d (%s)", req.Method, req.URL, resp.StatusCode, time.Since(start))
+
+			return resp, nil
+		}
+	}
+}
+
+// MetricsHook is invoked once per request/form call, after all retries,
+// with the final status code (0 on transport failure) and total duration.
+type MetricsHook func(method, path string, statusCode int, duration time.Duration)
+
+// SetMetricsHook registers a Prometheus-style metrics callback.
+func SetMetricsHook(hook MetricsHook) Option {
+	return func(b *Bookstack) {
+		b.metrics = hook
+	}
+}
+
+func metricsMiddleware(hook MetricsHook) Middleware {
+	return func(next RoundTrip) RoundTrip {
+		if hook == nil {
+			return next
+		}
+
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+
+			start := time.Now()
+
+			resp, err := next(ctx, req)
+
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+
+			hook(req.Method, req.URL.Path, statusCode, time.Since(start))
+
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package bookstack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func fastRetryPolicy(maxAttempts int, retryable int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:          maxAttempts,
+		BaseDelay:            time.Millisecond,
+		MaxDelay:             5 * time.Millisecond,
+		RetryableStatusCodes: map[int]bool{retryable: true},
+	}
+}
+
+func TestRetryMiddlewareRetriesRetryableStatus(t *testing.T) {
+	policy := fastRetryPolicy(3, http.StatusServiceUnavailable)
+
+	var calls int
+	terminal := RoundTrip(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		calls++
+
+		rec := httptest.NewRecorder()
+		if calls < 3 {
+			rec.Code = http.StatusServiceUnavailable
+		} else {
+			rec.Code = http.StatusOK
+		}
+
+		return rec.Result(), nil
+	})
+
+	mw := retryMiddleware(policy)(terminal)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := mw(context.Background(), req)
+	if err != nil {
+		t.Fatalf("mw() error = %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if calls != 3 {
+		t.Errorf("terminal called %d times, want 3", calls)
+	}
+}
+
+func TestRetryMiddlewareGivesUpAfterMaxAttempts(t *testing.T) {
+	policy := fastRetryPolicy(2, http.StatusServiceUnavailable)
+
+	var calls int
+	terminal := RoundTrip(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		calls++
+		rec := httptest.NewRecorder()
+		rec.Code = http.StatusServiceUnavailable
+		return rec.Result(), nil
+	})
+
+	mw := retryMiddleware(policy)(terminal)
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+
+	resp, err := mw(context.Background(), req)
+	if err != nil {
+		t.Fatalf("mw() error = %v", err)
+	}
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("final status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	if calls != 2 {
+		t.Errorf("terminal called %d times, want exactly MaxAttempts (2)", calls)
+	}
+}
+
+// TestMetricsMiddlewareMeasuresWholeCallNotEachAttempt composes metrics
+// around retry, the order chain() uses, and checks that the hook fires
+// once for the logical call, not once per HTTP attempt. It does not call
+// chain() itself.
+func TestMetricsMiddlewareMeasuresWholeCallNotEachAttempt(t *testing.T) {
+	policy := fastRetryPolicy(3, http.StatusServiceUnavailable)
+
+	var attempts int
+	terminal := RoundTrip(func(ctx context.Context, req *http.Request) (*http.Response, error) {
+		attempts++
+		rec := httptest.NewRecorder()
+		if attempts < 3 {
+			rec.Code = http.StatusServiceUnavailable
+		} else {
+			rec.Code = http.StatusOK
+		}
+		return rec.Result(), nil
+	})
+
+	var hookCalls int
+	hook := MetricsHook(func(method, path string, statusCode int, duration time.Duration) {
+		hookCalls++
+	})
+
+	chain := metricsMiddleware(hook)(retryMiddleware(policy)(terminal))
+
+	req, _ := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com", nil)
+
+	if _, err := chain(context.Background(), req); err != nil {
+		t.Fatalf("chain() error = %v", err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("attempts = %d, want 3", attempts)
+	}
+
+	if hookCalls != 1 {
+		t.Errorf("metrics hook called %d times, want 1 (once for the whole call, not per attempt)", hookCalls)
+	}
+}
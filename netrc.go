@@ -0,0 +1,133 @@
+package bookstack
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+// ErrNetrcNotFound is returned by SetTokenFromNetrc when the netrc file has
+// no entry for the requested machine.
+var ErrNetrcNotFound = errors.New("bookstack: no matching netrc entry")
+
+// SetTokenFromNetrc loads tokenID/tokenSecret from the login/password
+// fields of the netrc entry for machine (typically the Bookstack site's
+// hostname). It reads $NETRC if set, otherwise ~/.netrc
+// (%USERPROFILE%\_netrc on Windows).
+//
+// Unlike the other Set* options, this returns a typed error instead of an
+// Option when the file is missing, unreadable, or has no matching machine,
+// so callers can fall back to another credential source such as
+// SetTokenFromEnv.
+func SetTokenFromNetrc(machine string) (Option, error) {
+
+	path := netrcPath()
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("bookstack: open netrc: %w", err)
+	}
+	defer f.Close()
+
+	login, password, err := parseNetrcMachine(f, machine)
+	if err != nil {
+		return nil, err
+	}
+
+	return SetToken(login, password), nil
+}
+
+// SetTokenFromEnv loads tokenID/tokenSecret from the BOOKSTACK_TOKEN_ID and
+// BOOKSTACK_TOKEN_SECRET environment variables, keeping credentials out of
+// code and shell history. It returns an error if either variable is unset
+// or empty so callers can fall back to another credential source.
+func SetTokenFromEnv() (Option, error) {
+
+	id := os.Getenv("BOOKSTACK_TOKEN_ID")
+	secret := os.Getenv("BOOKSTACK_TOKEN_SECRET")
+
+	if id == "" || secret == "" {
+		return nil, errors.New("bookstack: BOOKSTACK_TOKEN_ID/BOOKSTACK_TOKEN_SECRET not set")
+	}
+
+	return SetToken(id, secret), nil
+}
+
+func netrcPath() string {
+
+	if p := os.Getenv("NETRC"); p != "" {
+		return p
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	if runtime.GOOS == "windows" {
+		return home + `\_netrc`
+	}
+
+	return home + "/.netrc"
+}
+
+// parseNetrcMachine does a minimal, whitespace-tokenized scan of a netrc
+// file for the login/password of the given machine. It does not support
+// macdef blocks.
+func parseNetrcMachine(r io.Reader, machine string) (login, password string, err error) {
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+
+	var field string
+	var active bool
+	var matchedSpecific bool
+	var found bool
+
+	for scanner.Scan() {
+
+		tok := scanner.Text()
+
+		switch tok {
+		case "machine", "default", "login", "password", "account":
+			field = tok
+			if tok == "default" {
+				// default only applies if no specific machine matched
+				// anywhere earlier in the file.
+				active = !matchedSpecific
+			}
+			continue
+		}
+
+		switch field {
+		case "machine":
+			active = tok == machine
+			if active {
+				matchedSpecific = true
+			}
+		case "login":
+			if active {
+				login = tok
+				found = true
+			}
+		case "password":
+			if active {
+				password = tok
+				found = true
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("bookstack: read netrc: %w", err)
+	}
+
+	if !found {
+		return "", "", fmt.Errorf("%w: %q", ErrNetrcNotFound, machine)
+	}
+
+	return login, password, nil
+}
@@ -0,0 +1,87 @@
+package bookstack
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseNetrcMachine(t *testing.T) {
+	const netrc = `
+machine bookstack.example.com
+login abc-id
+password abc-secret
+
+machine other.example.com
+login other-id
+password other-secret
+
+default
+login default-id
+password default-secret
+`
+
+	cases := []struct {
+		name         string
+		machine      string
+		wantLogin    string
+		wantPassword string
+		wantErr      bool
+	}{
+		{"matching machine", "bookstack.example.com", "abc-id", "abc-secret", false},
+		{"different machine", "other.example.com", "other-id", "other-secret", false},
+		{"unknown machine falls through to default", "nope.example.com", "default-id", "default-secret", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			login, password, err := parseNetrcMachine(strings.NewReader(netrc), c.machine)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, c.wantErr)
+			}
+
+			if login != c.wantLogin || password != c.wantPassword {
+				t.Errorf("got (%q, %q), want (%q, %q)", login, password, c.wantLogin, c.wantPassword)
+			}
+		})
+	}
+}
+
+func TestParseNetrcMachineNotFound(t *testing.T) {
+	const netrc = `
+machine bookstack.example.com
+login abc-id
+password abc-secret
+`
+
+	_, _, err := parseNetrcMachine(strings.NewReader(netrc), "nope.example.com")
+	if !errors.Is(err, ErrNetrcNotFound) {
+		t.Fatalf("err = %v, want ErrNetrcNotFound", err)
+	}
+}
+
+func TestSetTokenFromEnvMissing(t *testing.T) {
+	t.Setenv("BOOKSTACK_TOKEN_ID", "")
+	t.Setenv("BOOKSTACK_TOKEN_SECRET", "")
+
+	if _, err := SetTokenFromEnv(); err == nil {
+		t.Fatal("SetTokenFromEnv() with unset vars returned nil error")
+	}
+}
+
+func TestSetTokenFromEnv(t *testing.T) {
+	t.Setenv("BOOKSTACK_TOKEN_ID", "env-id")
+	t.Setenv("BOOKSTACK_TOKEN_SECRET", "env-secret")
+
+	opt, err := SetTokenFromEnv()
+	if err != nil {
+		t.Fatalf("SetTokenFromEnv() error = %v", err)
+	}
+
+	b := &Bookstack{}
+	opt(b)
+
+	if b.tokenID != "env-id" || b.tokenSecret != "env-secret" {
+		t.Errorf("got (%q, %q), want (%q, %q)", b.tokenID, b.tokenSecret, "env-id", "env-secret")
+	}
+}
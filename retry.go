@@ -0,0 +1,117 @@
+package bookstack
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how Bookstack.request and Bookstack.form retry
+// failed calls. The zero value disables retries (MaxAttempts < 1 is
+// treated as a single attempt).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	// one. A value <= 1 disables retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. Subsequent delays
+	// double, capped at MaxDelay, and are jittered by up to 50%.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the computed backoff delay.
+	MaxDelay time.Duration
+
+	// RetryableStatusCodes lists the HTTP status codes that trigger a
+	// retry, e.g. 429 and the 5xxs.
+	RetryableStatusCodes map[int]bool
+
+	// RespectRetryAfter honors a Retry-After response header (seconds or
+	// an HTTP-date) in place of the computed backoff delay.
+	RespectRetryAfter bool
+}
+
+// DefaultRetryPolicy retries 429, 500, 502, 503 and 504 responses up to 4
+// attempts with jittered exponential backoff from a 200ms base delay capped
+// at 5s, honoring Retry-After.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		RetryableStatusCodes: map[int]bool{
+			http.StatusTooManyRequests:     true,
+			http.StatusInternalServerError: true,
+			http.StatusBadGateway:          true,
+			http.StatusServiceUnavailable:  true,
+			http.StatusGatewayTimeout:      true,
+		},
+		RespectRetryAfter: true,
+	}
+}
+
+// SetRetry enables retrying retriable responses from request/form calls
+// according to policy.
+func SetRetry(policy RetryPolicy) Option {
+	return func(b *Bookstack) {
+		b.retry = policy
+	}
+}
+
+func (p RetryPolicy) attempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) retryable(statusCode int) bool {
+	return p.RetryableStatusCodes[statusCode]
+}
+
+// backoff returns the jittered delay to use before the (attempt+1)th
+// attempt, attempt being 0-based.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+// retryAfter parses a Retry-After header (seconds or HTTP-date), reporting
+// whether one was present.
+func retryAfter(header http.Header) (time.Duration, bool) {
+	h := header.Get("Retry-After")
+	if h == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(h); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if when, err := http.ParseTime(h); err == nil {
+		return time.Until(when), true
+	}
+
+	return 0, false
+}
+
+// sleep waits for d, returning ctx.Err() if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
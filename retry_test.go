@@ -0,0 +1,112 @@
+package bookstack
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyAttempts(t *testing.T) {
+	cases := []struct {
+		name string
+		max  int
+		want int
+	}{
+		{"zero value disables retries", 0, 1},
+		{"negative treated as one attempt", -3, 1},
+		{"explicit attempts kept as-is", 5, 5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := RetryPolicy{MaxAttempts: c.max}
+			if got := p.attempts(); got != c.want {
+				t.Errorf("attempts() = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		d := p.backoff(attempt)
+
+		if d < 0 {
+			t.Fatalf("backoff(%d) = %s, want >= 0", attempt, d)
+		}
+
+		if d > p.MaxDelay {
+			t.Fatalf("backoff(%d) = %s, want <= MaxDelay %s", attempt, d, p.MaxDelay)
+		}
+	}
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	p := DefaultRetryPolicy()
+
+	for _, code := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable} {
+		if !p.retryable(code) {
+			t.Errorf("retryable(%d) = false, want true", code)
+		}
+	}
+
+	for _, code := range []int{http.StatusOK, http.StatusNotFound, http.StatusBadRequest} {
+		if p.retryable(code) {
+			t.Errorf("retryable(%d) = true, want false", code)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		wantOK bool
+		want   time.Duration
+	}{
+		{"absent", "", false, 0},
+		{"seconds", "5", true, 5 * time.Second},
+		{"http-date", time.Now().UTC().Add(10 * time.Second).Format(http.TimeFormat), true, 10 * time.Second},
+		{"garbage", "not-a-valid-value", false, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := http.Header{}
+			if c.header != "" {
+				h.Set("Retry-After", c.header)
+			}
+
+			got, ok := retryAfter(h)
+			if ok != c.wantOK {
+				t.Fatalf("retryAfter() ok = %v, want %v", ok, c.wantOK)
+			}
+
+			if !ok {
+				return
+			}
+
+			if diff := got - c.want; diff < -time.Second || diff > time.Second {
+				t.Errorf("retryAfter() = %s, want ~%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSleepRespectsContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := sleep(ctx, time.Hour); err == nil {
+		t.Fatal("sleep() with a canceled context returned nil error, want context.Canceled")
+	}
+}
+
+func TestSleepZeroDelay(t *testing.T) {
+	if err := sleep(context.Background(), 0); err != nil {
+		t.Fatalf("sleep(0) = %v, want nil", err)
+	}
+}
@@ -0,0 +1,146 @@
+package bookstack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSplitChunks(t *testing.T) {
+	cases := []struct {
+		name      string
+		total     int64
+		chunkSize int
+		want      []chunk
+	}{
+		{"empty blob yields a single zero-size chunk", 0, 10, []chunk{{offset: 0, size: 0}}},
+		{"exact multiple", 20, 10, []chunk{{offset: 0, size: 10}, {offset: 10, size: 10}}},
+		{"remainder in last chunk", 25, 10, []chunk{{offset: 0, size: 10}, {offset: 10, size: 10}, {offset: 20, size: 5}}},
+		{"smaller than one chunk", 3, 10, []chunk{{offset: 0, size: 3}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := splitChunks(c.total, c.chunkSize)
+
+			if len(got) != len(c.want) {
+				t.Fatalf("splitChunks() = %+v, want %+v", got, c.want)
+			}
+
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("chunk %d = %+v, want %+v", i, got[i], c.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestStreamChunksOrderAndHash(t *testing.T) {
+	data := make([]byte, 55)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	blob := NewBytesBlob(data)
+	chunks := splitChunks(blob.Size(), 10)
+
+	results := streamChunks(context.Background(), blob, chunks, 3)
+
+	var gotIndex int
+	for r := range results {
+		if r.err != nil {
+			t.Fatalf("chunk %d: %v", r.index, r.err)
+		}
+
+		if r.index != gotIndex {
+			t.Fatalf("results arrived out of order: got index %d, want %d", r.index, gotIndex)
+		}
+
+		c := chunks[r.index]
+		want := data[c.offset : c.offset+c.size]
+
+		sum := sha256.Sum256(want)
+		wantHash := hex.EncodeToString(sum[:])
+
+		if r.hash != wantHash {
+			t.Errorf("chunk %d hash = %s, want %s", r.index, r.hash, wantHash)
+		}
+
+		gotIndex++
+	}
+
+	if gotIndex != len(chunks) {
+		t.Errorf("streamed %d chunks, want %d", gotIndex, len(chunks))
+	}
+}
+
+// TestStreamChunksBoundsReadAhead pins the fix for a real backpressure bug:
+// a slow (or absent) consumer must not let streamChunks read, hash, and
+// buffer the whole blob in memory - read-ahead is bounded to roughly
+// concurrency chunks regardless of how fast the workers could otherwise go.
+func TestStreamChunksBoundsReadAhead(t *testing.T) {
+	const chunkSize = 16
+	const numChunks = 50
+	const concurrency = 4
+
+	data := make([]byte, chunkSize*numChunks)
+
+	var reads int32
+	blob := &countingBlob{Blob: NewBytesBlob(data), reads: &reads}
+
+	chunks := splitChunks(blob.Size(), chunkSize)
+	results := streamChunks(context.Background(), blob, chunks, concurrency)
+
+	// Deliberately don't drain results - if the pipeline raced ahead of
+	// the consumer, every chunk would already be read by now.
+	time.Sleep(100 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&reads); got > concurrency+1 {
+		t.Errorf("streamChunks read %d of %d chunks ahead of an idle consumer with concurrency %d, want at most %d", got, numChunks, concurrency, concurrency+1)
+	}
+
+	for range results {
+		// Drain so every worker goroutine exits cleanly.
+	}
+}
+
+type countingBlob struct {
+	Blob
+	reads *int32
+}
+
+func (b *countingBlob) ReadAt(p []byte, off int64) (int, error) {
+	atomic.AddInt32(b.reads, 1)
+	return b.Blob.ReadAt(p, off)
+}
+
+func TestStreamChunksCancellationDoesNotLeak(t *testing.T) {
+	data := make([]byte, 1<<20)
+	blob := NewBytesBlob(data)
+	chunks := splitChunks(blob.Size(), 1024)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := streamChunks(ctx, blob, chunks, 4)
+
+	// Take exactly one result, then cancel and stop reading - every
+	// worker goroutine must wind down and the channel must close on its
+	// own rather than blocking forever on a send nobody will receive.
+	<-results
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		if ok {
+			// Draining further results is fine; keep going until closed.
+			for range results {
+			}
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("streamChunks did not close its output channel after cancellation")
+	}
+}